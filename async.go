@@ -0,0 +1,132 @@
+package ln
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// AsyncFilterStats reports the running counters an AsyncFilter keeps.
+type AsyncFilterStats struct {
+	Enqueued  int64
+	Delivered int64
+	Dropped   int64
+}
+
+// AsyncFilter wraps another Filter so its Apply runs on a background
+// goroutine instead of the caller's. This keeps a slow or networked
+// inner Filter (syslog, HTTP, journald, ...) from becoming a latency
+// hazard on the hot path that calls Logger.Log.
+//
+// Apply always returns true: once an Event is accepted it's either
+// delivered or dropped asynchronously, and either way the rest of the
+// calling Logger's filter chain should keep running.
+type AsyncFilter struct {
+	inner  Filter
+	onDrop func(Event)
+	events chan Event
+	done   chan struct{}
+
+	mu        sync.RWMutex
+	closed    bool
+	closeOnce sync.Once
+
+	enqueued  int64
+	delivered int64
+	dropped   int64
+}
+
+// NewAsyncFilter starts a goroutine draining a channel of Events, of
+// capacity bufSize, into inner.Apply, and returns a Filter that feeds it.
+// If the channel is full, the Event is dropped and onDrop, if non-nil,
+// is called with it. A negative bufSize, which would otherwise panic in
+// make, is treated as 0 (unbuffered).
+func NewAsyncFilter(inner Filter, bufSize int, onDrop func(Event)) Filter {
+	if bufSize < 0 {
+		bufSize = 0
+	}
+
+	af := &AsyncFilter{
+		inner:  inner,
+		onDrop: onDrop,
+		events: make(chan Event, bufSize),
+		done:   make(chan struct{}),
+	}
+	go af.drain()
+	return af
+}
+
+func (a *AsyncFilter) drain() {
+	defer close(a.done)
+	for e := range a.events {
+		if a.inner.Apply(e) {
+			atomic.AddInt64(&a.delivered, 1)
+		}
+	}
+}
+
+// Apply implements the Filter interface.
+func (a *AsyncFilter) Apply(e Event) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		a.drop(e)
+		return true
+	}
+
+	select {
+	case a.events <- e:
+		atomic.AddInt64(&a.enqueued, 1)
+	default:
+		a.drop(e)
+	}
+
+	return true
+}
+
+func (a *AsyncFilter) drop(e Event) {
+	atomic.AddInt64(&a.dropped, 1)
+	if a.onDrop != nil {
+		a.onDrop(e)
+	}
+}
+
+// Stats returns a snapshot of the Enqueued, Delivered and Dropped
+// counters.
+func (a *AsyncFilter) Stats() AsyncFilterStats {
+	return AsyncFilterStats{
+		Enqueued:  atomic.LoadInt64(&a.enqueued),
+		Delivered: atomic.LoadInt64(&a.delivered),
+		Dropped:   atomic.LoadInt64(&a.dropped),
+	}
+}
+
+// Run implements the Filter interface.
+func (a *AsyncFilter) Run() {}
+
+// Close implements the Filter interface, flushing whatever is already
+// queued with no deadline. Use Shutdown if you need control over how
+// long to wait.
+func (a *AsyncFilter) Close() {
+	a.Shutdown(context.Background())
+}
+
+// Shutdown stops accepting new Events and waits for the ones already
+// queued to be delivered to inner, or for ctx to be done, whichever
+// comes first.
+func (a *AsyncFilter) Shutdown(ctx context.Context) error {
+	a.closeOnce.Do(func() {
+		a.mu.Lock()
+		a.closed = true
+		close(a.events)
+		a.mu.Unlock()
+	})
+
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,96 @@
+package ln
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingFilter struct {
+	mu    sync.Mutex
+	n     int
+	delay time.Duration
+}
+
+func (c *countingFilter) Apply(e Event) bool {
+	time.Sleep(c.delay)
+	c.mu.Lock()
+	c.n++
+	c.mu.Unlock()
+	return true
+}
+
+func (c *countingFilter) Run()   {}
+func (c *countingFilter) Close() {}
+
+func (c *countingFilter) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.n
+}
+
+func TestAsyncFilterDeliversAndFlushesOnClose(t *testing.T) {
+	inner := &countingFilter{delay: time.Millisecond}
+	af := NewAsyncFilter(inner, 10, nil)
+
+	for i := 0; i < 5; i++ {
+		if !af.Apply(Event{Message: "x"}) {
+			t.Fatal("Apply returned false")
+		}
+	}
+
+	if err := af.(*AsyncFilter).Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if got := inner.count(); got != 5 {
+		t.Fatalf("expected 5 delivered, got %d", got)
+	}
+
+	stats := af.(*AsyncFilter).Stats()
+	if stats.Enqueued != 5 || stats.Delivered != 5 || stats.Dropped != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestAsyncFilterDropsOnOverflow(t *testing.T) {
+	var dropped []Event
+	var mu sync.Mutex
+
+	inner := &countingFilter{delay: 50 * time.Millisecond}
+	af := NewAsyncFilter(inner, 1, func(e Event) {
+		mu.Lock()
+		dropped = append(dropped, e)
+		mu.Unlock()
+	})
+
+	for i := 0; i < 10; i++ {
+		af.Apply(Event{Message: "x"})
+	}
+
+	af.(*AsyncFilter).Close()
+
+	mu.Lock()
+	n := len(dropped)
+	mu.Unlock()
+
+	if n == 0 {
+		t.Fatal("expected at least one dropped Event under overflow")
+	}
+
+	stats := af.(*AsyncFilter).Stats()
+	if stats.Dropped != int64(n) {
+		t.Fatalf("Stats().Dropped (%d) doesn't match onDrop calls (%d)", stats.Dropped, n)
+	}
+}
+
+func TestNewAsyncFilterNegativeBufSizeDoesNotPanic(t *testing.T) {
+	inner := &countingFilter{}
+	af := NewAsyncFilter(inner, -1, nil)
+	defer af.(*AsyncFilter).Close()
+
+	if !af.Apply(Event{Message: "x"}) {
+		t.Fatal("Apply returned false")
+	}
+}
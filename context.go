@@ -0,0 +1,22 @@
+package ln
+
+import "context"
+
+type ctxKey struct{}
+
+// NewContext returns a copy of ctx that carries l, retrievable via
+// FromContext. It lets request-scoped fields (set on l via With) flow
+// through a call graph without threading a Logger through every
+// function signature.
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the Logger previously stored in ctx by
+// NewContext, or DefaultLogger if ctx carries none.
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*Logger); ok {
+		return l
+	}
+	return DefaultLogger
+}
@@ -0,0 +1,25 @@
+package ln
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestContext(t *testing.T) {
+	out := bytes.Buffer{}
+	l := (&Logger{Pri: PriInfo, Filters: []Filter{NewWriterFilter(&out, nil)}}).With(F{"trace_id": "xyz"})
+
+	ctx := NewContext(context.Background(), l)
+
+	FromContext(ctx).Info("hello")
+	if !bytes.Contains(out.Bytes(), []byte(`trace_id=xyz`)) {
+		t.Fatalf("expected trace_id in %s", out.Bytes())
+	}
+}
+
+func TestFromContextDefault(t *testing.T) {
+	if FromContext(context.Background()) != DefaultLogger {
+		t.Fatal("expected FromContext to fall back to DefaultLogger")
+	}
+}
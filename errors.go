@@ -0,0 +1,53 @@
+package ln
+
+import (
+	"errors"
+	"fmt"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+// stackTracer matches the interface github.com/pkg/errors attaches to
+// errors it creates or wraps.
+type stackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
+
+// Err expands an error into fields suitable for structured logging:
+// error (its message) and error_type (its concrete type). If err, or
+// anything it wraps via errors.Unwrap, implements stackTracer (as
+// github.com/pkg/errors errors do), _stack holds the outermost such
+// stack found. If err wraps others, error_chain holds the unwrapped
+// messages, outermost first.
+func Err(err error) F {
+	if err == nil {
+		return F{}
+	}
+
+	f := F{
+		"error":      err.Error(),
+		"error_type": fmt.Sprintf("%T", err),
+	}
+
+	var chain []string
+	for cur := err; cur != nil; cur = errors.Unwrap(cur) {
+		if _, ok := f["_stack"]; !ok {
+			if st, ok := cur.(stackTracer); ok {
+				trace := st.StackTrace()
+				stack := make([]string, len(trace))
+				for i, fr := range trace {
+					stack[i] = fmt.Sprintf("%+v", fr)
+				}
+				f["_stack"] = stack
+			}
+		}
+		if cur != err {
+			chain = append(chain, cur.Error())
+		}
+	}
+	if len(chain) > 0 {
+		f["error_chain"] = chain
+	}
+
+	return f
+}
@@ -0,0 +1,59 @@
+package ln
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+func TestErrBasic(t *testing.T) {
+	f := Err(errors.New("boom"))
+
+	if f["error"] != "boom" {
+		t.Fatalf("error: got %v", f["error"])
+	}
+	if f["error_type"] != "*errors.errorString" {
+		t.Fatalf("error_type: got %v", f["error_type"])
+	}
+	if _, ok := f["_stack"]; ok {
+		t.Fatalf("unexpected _stack on a plain error: %+v", f)
+	}
+	if _, ok := f["error_chain"]; ok {
+		t.Fatalf("unexpected error_chain on a plain error: %+v", f)
+	}
+}
+
+func TestErrChain(t *testing.T) {
+	root := errors.New("root cause")
+	wrapped := fmt.Errorf("wrapped: %w", root)
+
+	f := Err(wrapped)
+
+	chain, ok := f["error_chain"].([]string)
+	if !ok || len(chain) != 1 || chain[0] != "root cause" {
+		t.Fatalf("error_chain: got %#v", f["error_chain"])
+	}
+}
+
+func TestErrStackTrace(t *testing.T) {
+	f := Err(pkgerrors.New("boom"))
+
+	stack, ok := f["_stack"].([]string)
+	if !ok || len(stack) == 0 {
+		t.Fatalf("expected a non-empty _stack, got %#v", f["_stack"])
+	}
+}
+
+func TestErrStackTraceThroughWrap(t *testing.T) {
+	cause := pkgerrors.New("root cause")
+	wrapped := fmt.Errorf("context: %w", cause)
+
+	f := Err(wrapped)
+
+	stack, ok := f["_stack"].([]string)
+	if !ok || len(stack) == 0 {
+		t.Fatalf("expected a non-empty _stack from the wrapped cause, got %#v", f["_stack"])
+	}
+}
@@ -0,0 +1,187 @@
+package ln
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"golang.org/x/term"
+)
+
+var (
+	// DefaultTimeFormat represents the way in which time will be formatted by default
+	DefaultTimeFormat = time.RFC3339
+)
+
+// Formatter defines the formatting of events
+type Formatter interface {
+	Format(Event) ([]byte, error)
+}
+
+// DefaultFormatter is the default way in which to format events
+var DefaultFormatter Formatter
+
+func init() {
+	DefaultFormatter = NewTextFormatter()
+}
+
+// TextFormatter formats events as key value pairs.
+// Any remaining text not wrapped in an instance of `F` will be
+// placed at the end.
+type TextFormatter struct {
+	TimeFormat string
+
+	// Color, when true, wraps the priority in an ANSI color code
+	// matching its severity.
+	Color bool
+}
+
+// NewTextFormatter returns a Formatter that outputs as text.
+func NewTextFormatter() Formatter {
+	return &TextFormatter{TimeFormat: DefaultTimeFormat}
+}
+
+// priorityColors are the ANSI color codes used by TextFormatter to
+// highlight a priority when Color is enabled.
+var priorityColors = map[Priority]string{
+	PriEmergency: "31;1", // bold red
+	PriAlert:     "31;1", // bold red
+	PriCritical:  "31;1", // bold red
+	PriError:     "31",   // red
+	PriWarning:   "33",   // yellow
+	PriNotice:    "36",   // cyan
+	PriInfo:      "32",   // green
+	PriDebug:     "90",   // bright black
+}
+
+func (t *TextFormatter) colorize(p Priority, s string) string {
+	if !t.Color {
+		return s
+	}
+	code, ok := priorityColors[p]
+	if !ok {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[0m"
+}
+
+// Format implements the Formatter interface
+func (t *TextFormatter) Format(e Event) ([]byte, error) {
+	var writer bytes.Buffer
+
+	writer.WriteString("time=\"")
+	writer.WriteString(e.Time.Format(t.TimeFormat))
+	writer.WriteString("\" ")
+
+	writer.WriteString("priority=")
+	writer.WriteString(t.colorize(e.Pri, e.Pri.String()))
+
+	for k, v := range e.Data {
+		writer.WriteByte(' ')
+		if shouldQuote(k) {
+			writer.WriteString(fmt.Sprintf("%q", k))
+		} else {
+			writer.WriteString(k)
+		}
+
+		writer.WriteByte('=')
+
+		switch v.(type) {
+		case string:
+			vs, _ := v.(string)
+			if shouldQuote(vs) {
+				fmt.Fprintf(&writer, "%q", vs)
+			} else {
+				writer.WriteString(vs)
+			}
+		case error:
+			tmperr, _ := v.(error)
+			es := tmperr.Error()
+
+			if shouldQuote(es) {
+				fmt.Fprintf(&writer, "%q", es)
+			} else {
+				writer.WriteString(es)
+			}
+		case time.Time:
+			tmptime, _ := v.(time.Time)
+			writer.WriteString(tmptime.Format(time.RFC3339))
+		default:
+			fmt.Fprint(&writer, v)
+		}
+	}
+
+	if len(e.Message) > 0 {
+		fmt.Fprintf(&writer, " _msg=%q", e.Message)
+	}
+
+	writer.WriteByte('\n')
+	return writer.Bytes(), nil
+}
+
+func shouldQuote(s string) bool {
+	for _, b := range s {
+		if !((b >= 'A' && b <= 'Z') ||
+			(b >= 'a' && b <= 'z') ||
+			(b >= '0' && b <= '9') ||
+			(b == '-' || b == '.' || b == '#' ||
+				b == '/' || b == '_')) {
+			return true
+		}
+	}
+	return false
+}
+
+// JSONFormatter formats events as a single JSON object per line, with
+// time, pri and msg alongside the merged Data fields.
+type JSONFormatter struct {
+	TimeFormat string
+}
+
+// NewJSONFormatter returns a Formatter that outputs one JSON object per
+// Event.
+func NewJSONFormatter() Formatter {
+	return &JSONFormatter{TimeFormat: time.RFC3339Nano}
+}
+
+// Format implements the Formatter interface
+func (j *JSONFormatter) Format(e Event) ([]byte, error) {
+	fields := make(map[string]interface{}, len(e.Data)+3)
+	for k, v := range e.Data {
+		if err, ok := v.(error); ok {
+			fields[k] = err.Error()
+		} else {
+			fields[k] = v
+		}
+	}
+
+	// Set after copying Data so these always win on key collision.
+	fields["time"] = e.Time.Format(j.TimeFormat)
+	fields["pri"] = e.Pri.String()
+	fields["msg"] = e.Message
+
+	var writer bytes.Buffer
+	// json.Marshal sorts map keys, so output is deterministic.
+	if err := json.NewEncoder(&writer).Encode(fields); err != nil {
+		return nil, err
+	}
+
+	return writer.Bytes(), nil
+}
+
+// NewAutoColorTextFormatter returns a TextFormatter with Color enabled
+// when out is a terminal attached to a TTY. It's an explicit opt-in:
+// pass its result to NewWriterFilter to get colorized output on an
+// interactive terminal and plain text otherwise. NewWriterFilter's own
+// nil-formatter fallback does not do this automatically, so existing
+// callers' output doesn't change underneath them.
+func NewAutoColorTextFormatter(out io.Writer) Formatter {
+	tf := &TextFormatter{TimeFormat: DefaultTimeFormat}
+	if f, ok := out.(*os.File); ok {
+		tf.Color = term.IsTerminal(int(f.Fd()))
+	}
+	return tf
+}
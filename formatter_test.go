@@ -0,0 +1,75 @@
+package ln
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewWriterFilterNilFormatterHasNoColor(t *testing.T) {
+	var out strings.Builder
+	wf := NewWriterFilter(&out, nil)
+
+	tf, ok := wf.Formatter.(*TextFormatter)
+	if !ok {
+		t.Fatalf("expected a *TextFormatter, got %T", wf.Formatter)
+	}
+	if tf.Color {
+		t.Fatalf("nil-formatter fallback must not enable color, for backwards compatibility")
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	f := NewJSONFormatter()
+
+	e := Event{
+		Pri:     PriWarning,
+		Time:    time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Message: "disk almost full",
+		Data:    F{"free_bytes": 1024, "err": errFoo{}},
+	}
+
+	out, err := f.Format(e)
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("output isn't valid JSON: %v (%s)", err, out)
+	}
+
+	if got["pri"] != "warn" {
+		t.Fatalf("pri: got %v", got["pri"])
+	}
+	if got["msg"] != "disk almost full" {
+		t.Fatalf("msg: got %v", got["msg"])
+	}
+	if got["time"] != "2020-01-02T03:04:05Z" {
+		t.Fatalf("time: got %v", got["time"])
+	}
+	if got["free_bytes"] != float64(1024) {
+		t.Fatalf("free_bytes: got %v", got["free_bytes"])
+	}
+	if got["err"] != "foo failed" {
+		t.Fatalf("err: got %v", got["err"])
+	}
+}
+
+func TestTextFormatterColor(t *testing.T) {
+	tf := &TextFormatter{TimeFormat: DefaultTimeFormat, Color: true}
+
+	out, err := tf.Format(Event{Pri: PriError, Message: "boom"})
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+
+	if !strings.Contains(string(out), "\x1b[31merror\x1b[0m") {
+		t.Fatalf("expected colorized priority, got %s", out)
+	}
+}
+
+type errFoo struct{}
+
+func (errFoo) Error() string { return "foo failed" }
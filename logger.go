@@ -51,6 +51,32 @@ func (p Priority) String() string {
 type Logger struct {
 	Pri     Priority
 	Filters []Filter
+
+	// ReportCaller, when true, adds _file/_line/_func fields identifying
+	// the call site to any Event at or more severe than ReportCallerAt.
+	ReportCaller   bool
+	ReportCallerAt Priority
+
+	base F
+}
+
+// With returns a shallow copy of l whose base fields include data merged
+// on top of l's own base fields. The returned Logger merges those fields
+// into every Event it logs; per-call F{} values passed to Log (and its
+// Emergency/Alert/.../Debug shorthands) take precedence over them. With
+// is safe to chain.
+func (l *Logger) With(data F) *Logger {
+	base := make(F, len(l.base)+len(data))
+	for k, v := range l.base {
+		base[k] = v
+	}
+	for k, v := range data {
+		base[k] = v
+	}
+
+	nl := *l
+	nl.base = base
+	return &nl
 }
 
 // DefaultLogger is the default implementation of Logger
@@ -108,6 +134,13 @@ func (l *Logger) Log(p Priority, xs ...interface{}) {
 	var bits []interface{}
 	event := Event{Pri: p, Time: time.Now()}
 
+	if len(l.base) > 0 {
+		event.Data = make(F, len(l.base))
+		for k, v := range l.base {
+			event.Data[k] = v
+		}
+	}
+
 	addF := func(bf F) {
 		if event.Data == nil {
 			event.Data = bf
@@ -131,14 +164,14 @@ func (l *Logger) Log(p Priority, xs ...interface{}) {
 
 	event.Message = fmt.Sprint(bits...)
 
-	if l.Pri == PriDebug {
+	if l.ReportCaller && p <= l.ReportCallerAt {
 		frame := callersFrame()
 		if event.Data == nil {
 			event.Data = make(F)
 		}
-		event.Data["_lineno"] = frame.lineno
-		event.Data["_function"] = frame.function
-		event.Data["_filename"] = frame.filename
+		event.Data["_file"] = frame.filename
+		event.Data["_line"] = frame.lineno
+		event.Data["_func"] = frame.function
 	}
 
 	l.filter(event)
@@ -0,0 +1,165 @@
+package ln
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func setup(t *testing.T) (*bytes.Buffer, func()) {
+	out := bytes.Buffer{}
+	oldFilters := DefaultLogger.Filters
+	DefaultLogger.Filters = []Filter{NewWriterFilter(&out, nil)}
+	return &out, func() {
+		DefaultLogger.Filters = oldFilters
+	}
+}
+
+func TestSimpleError(t *testing.T) {
+	out, teardown := setup(t)
+	defer teardown()
+
+	Info(F{"err": fmt.Errorf("This is an Error!!!")}, "fooey", F{"bar": "foo"})
+	data := []string{
+		`err="This is an Error!!!"`,
+		`fooey`,
+		`bar=foo`,
+	}
+
+	for _, line := range data {
+		if !bytes.Contains(out.Bytes(), []byte(line)) {
+			t.Fatalf("Bytes: %s not in %s", line, out.Bytes())
+		}
+	}
+}
+
+func TestTimeConversion(t *testing.T) {
+	out, teardown := setup(t)
+	defer teardown()
+
+	var zeroTime time.Time
+
+	Info(F{"zero": zeroTime})
+	data := []string{
+		`zero=0001-01-01T00:00:00Z`,
+	}
+
+	for _, line := range data {
+		if !bytes.Contains(out.Bytes(), []byte(line)) {
+			t.Fatalf("Bytes: %s not in %s", line, out.Bytes())
+		}
+	}
+}
+
+func TestDebug(t *testing.T) {
+	out, teardown := setup(t)
+	defer teardown()
+
+	oldPri := DefaultLogger.Pri
+	oldReportCaller := DefaultLogger.ReportCaller
+	oldReportCallerAt := DefaultLogger.ReportCallerAt
+	defer func() {
+		DefaultLogger.Pri = oldPri
+		DefaultLogger.ReportCaller = oldReportCaller
+		DefaultLogger.ReportCallerAt = oldReportCallerAt
+	}()
+
+	// set priority to Debug, and report the caller for Debug (and more
+	// severe) Events.
+	DefaultLogger.Pri = PriDebug
+	DefaultLogger.ReportCaller = true
+	DefaultLogger.ReportCallerAt = PriDebug
+	Debug(F{"err": fmt.Errorf("This is an Error!!!")})
+
+	data := []string{
+		`err="This is an Error!!!"`,
+		`_line=`,
+		`_func=ln.TestDebug`,
+		`_file=`,
+		`logger_test.go`,
+	}
+
+	for _, line := range data {
+		if !bytes.Contains(out.Bytes(), []byte(line)) {
+			t.Fatalf("Bytes: %s not in %s", line, out.Bytes())
+		}
+	}
+}
+
+func TestReportCallerThreshold(t *testing.T) {
+	out, teardown := setup(t)
+	defer teardown()
+
+	l := &Logger{Pri: PriInfo, Filters: []Filter{NewWriterFilter(out, nil)}, ReportCaller: true, ReportCallerAt: PriWarning}
+
+	l.Info("below threshold")
+	if bytes.Contains(out.Bytes(), []byte(`_func=`)) {
+		t.Fatalf("expected no caller info below threshold: %s", out.Bytes())
+	}
+
+	out.Reset()
+	l.Warning("at threshold")
+	if !bytes.Contains(out.Bytes(), []byte(`_func=ln.TestReportCallerThreshold`)) {
+		t.Fatalf("expected caller info at threshold: %s", out.Bytes())
+	}
+}
+
+func TestFer(t *testing.T) {
+	out, teardown := setup(t)
+	defer teardown()
+
+	underTest := foobar{Foo: 1, Bar: "quux"}
+
+	Info(underTest)
+	data := []string{
+		`foo=1`,
+		`bar=quux`,
+	}
+
+	for _, line := range data {
+		if !bytes.Contains(out.Bytes(), []byte(line)) {
+			t.Fatalf("Bytes: %s not in %s", line, out.Bytes())
+		}
+	}
+}
+
+type foobar struct {
+	Foo int
+	Bar string
+}
+
+func (f foobar) F() map[string]interface{} {
+	return map[string]interface{}{
+		"foo": f.Foo,
+		"bar": f.Bar,
+	}
+}
+
+func TestWith(t *testing.T) {
+	out := bytes.Buffer{}
+	l := &Logger{Pri: PriInfo, Filters: []Filter{NewWriterFilter(&out, nil)}}
+
+	reqLogger := l.With(F{"request_id": "abc123"}).With(F{"user_id": 42})
+	reqLogger.Info("handled", F{"user_id": 43})
+
+	data := []string{
+		`request_id=abc123`,
+		`user_id=43`,
+	}
+	for _, line := range data {
+		if !bytes.Contains(out.Bytes(), []byte(line)) {
+			t.Fatalf("Bytes: %s not in %s", line, out.Bytes())
+		}
+	}
+	if bytes.Contains(out.Bytes(), []byte(`user_id=42`)) {
+		t.Fatalf("per-call field didn't override base field: %s", out.Bytes())
+	}
+
+	// l itself must be unaffected by With.
+	out.Reset()
+	l.Info("unrelated")
+	if bytes.Contains(out.Bytes(), []byte(`request_id`)) {
+		t.Fatalf("With mutated the receiver: %s", out.Bytes())
+	}
+}
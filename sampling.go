@@ -0,0 +1,233 @@
+package ln
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// sampleKeyCacheSize bounds the number of distinct keys a SamplingFilter
+// tracks at once; least-recently-used keys are evicted beyond it.
+const sampleKeyCacheSize = 1024
+
+// SampleKeyFunc derives the bucket a SamplingFilter rate-limits by, from
+// an Event.
+type SampleKeyFunc func(Event) string
+
+// SamplingFilter wraps another Filter and bounds log volume under load.
+// Within each interval window it lets the first `first` Events matching
+// a given key through to inner, then only 1 out of every `thereafter`,
+// resetting on window rollover. At the end of a window it emits a
+// synthetic Event into inner recording how many were suppressed, so the
+// sink still has a record of what it didn't see. A background sweep
+// does this even for a key that's gone quiet, so a suppressed burst
+// followed by silence still gets its summary.
+type SamplingFilter struct {
+	inner      Filter
+	first      int
+	thereafter int
+	interval   time.Duration
+	keyFunc    SampleKeyFunc
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // front = most recently used
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type sampleEntry struct {
+	key         string
+	windowStart time.Time
+	pri         Priority
+	count       int
+	suppressed  int
+}
+
+// summaryEvent returns the synthetic Event recording entry's suppressed
+// count, and resets it for the next window. ok is false if there's
+// nothing to report. Callers must hold the owning SamplingFilter's mu.
+func (e *sampleEntry) summaryEvent(now time.Time) (Event, bool) {
+	var ev Event
+	ok := e.suppressed > 0
+	if ok {
+		ev = Event{
+			Pri:     e.pri,
+			Time:    now,
+			Message: fmt.Sprintf("suppressed %d events", e.suppressed),
+			Data:    F{"_sampled_key": e.key, "_suppressed": e.suppressed},
+		}
+	}
+	e.windowStart = now
+	e.count = 0
+	e.suppressed = 0
+	return ev, ok
+}
+
+// NewSamplingFilter returns a SamplingFilter keyed by (Priority,
+// Message). Use NewSamplingFilterFunc to key on something else.
+func NewSamplingFilter(inner Filter, first, thereafter int, interval time.Duration) Filter {
+	return NewSamplingFilterFunc(inner, first, thereafter, interval, defaultSampleKey)
+}
+
+// minSampleInterval is the floor NewSamplingFilterFunc clamps interval
+// to. time.NewTicker panics on a non-positive duration, and interval's
+// zero value (no interval given) is exactly that, so silently falling
+// back to a usable default is safer than crashing the caller's process.
+const minSampleInterval = time.Second
+
+// NewSamplingFilterFunc is NewSamplingFilter with a caller-supplied key
+// function in place of the default (Priority, Message) grouping.
+func NewSamplingFilterFunc(inner Filter, first, thereafter int, interval time.Duration, keyFunc SampleKeyFunc) Filter {
+	if interval <= 0 {
+		interval = minSampleInterval
+	}
+
+	s := &SamplingFilter{
+		inner:      inner,
+		first:      first,
+		thereafter: thereafter,
+		interval:   interval,
+		keyFunc:    keyFunc,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		done:       make(chan struct{}),
+	}
+	go s.sweep()
+	return s
+}
+
+func defaultSampleKey(e Event) string {
+	return fmt.Sprintf("%s|%s", e.Pri, e.Message)
+}
+
+// Apply implements the Filter interface.
+func (s *SamplingFilter) Apply(e Event) bool {
+	key := s.keyFunc(e)
+	now := time.Now()
+
+	var pending []Event
+
+	s.mu.Lock()
+	entry, evicted := s.entry(key, now)
+	if evicted != nil {
+		pending = append(pending, *evicted)
+	}
+
+	if now.Sub(entry.windowStart) >= s.interval {
+		if ev, ok := entry.summaryEvent(now); ok {
+			pending = append(pending, ev)
+		}
+	}
+	entry.pri = e.Pri
+
+	entry.count++
+	through := entry.count <= s.first
+	if !through && s.thereafter > 0 {
+		through = (entry.count-s.first)%s.thereafter == 0
+	}
+	if !through {
+		entry.suppressed++
+	}
+	s.mu.Unlock()
+
+	// inner.Apply runs outside s.mu so a slow or stalled inner Filter
+	// (a network sink, say) can't block every other key's Apply call on
+	// this one's lock, the same way AsyncFilter.drain keeps its channel
+	// read off the hot path.
+	for _, ev := range pending {
+		s.inner.Apply(ev)
+	}
+
+	if through {
+		return s.inner.Apply(e)
+	}
+	return true
+}
+
+// entry returns the sampleEntry for key, creating one (and evicting the
+// least recently used if the cache is full) if needed, and marks it
+// most-recently-used. If creating it evicted another entry with a
+// pending summary, that summary Event is returned for the caller to
+// apply once s.mu is released. Callers must hold s.mu.
+func (s *SamplingFilter) entry(key string, now time.Time) (*sampleEntry, *Event) {
+	if el, ok := s.entries[key]; ok {
+		s.lru.MoveToFront(el)
+		return el.Value.(*sampleEntry), nil
+	}
+
+	var evicted *Event
+	if s.lru.Len() >= sampleKeyCacheSize {
+		evicted = s.evictOldest(now)
+	}
+
+	entry := &sampleEntry{key: key, windowStart: now}
+	s.entries[key] = s.lru.PushFront(entry)
+	return entry, evicted
+}
+
+// evictOldest drops the least-recently-used entry, returning its
+// summary Event if it had anything suppressed. Callers must hold s.mu.
+func (s *SamplingFilter) evictOldest(now time.Time) *Event {
+	oldest := s.lru.Back()
+	if oldest == nil {
+		return nil
+	}
+	entry := oldest.Value.(*sampleEntry)
+	s.lru.Remove(oldest)
+	delete(s.entries, entry.key)
+
+	if ev, ok := entry.summaryEvent(now); ok {
+		return &ev
+	}
+	return nil
+}
+
+// sweep periodically flushes any entry whose window has ended, so a key
+// that goes quiet after a suppressed burst still gets its summary
+// Event even with no further Apply calls to trigger it.
+func (s *SamplingFilter) sweep() {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			s.flushExpired(now)
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *SamplingFilter) flushExpired(now time.Time) {
+	var pending []Event
+
+	s.mu.Lock()
+	for el := s.lru.Front(); el != nil; el = el.Next() {
+		entry := el.Value.(*sampleEntry)
+		if now.Sub(entry.windowStart) < s.interval {
+			continue
+		}
+		if ev, ok := entry.summaryEvent(now); ok {
+			pending = append(pending, ev)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, ev := range pending {
+		s.inner.Apply(ev)
+	}
+}
+
+// Run implements the Filter interface.
+func (s *SamplingFilter) Run() {}
+
+// Close implements the Filter interface, stopping the background sweep.
+func (s *SamplingFilter) Close() {
+	s.closeOnce.Do(func() {
+		close(s.done)
+	})
+}
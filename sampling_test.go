@@ -0,0 +1,129 @@
+package ln
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingFilter struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recordingFilter) Apply(e Event) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+	return true
+}
+
+func (r *recordingFilter) Run()   {}
+func (r *recordingFilter) Close() {}
+
+func (r *recordingFilter) snapshot() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Event, len(r.events))
+	copy(out, r.events)
+	return out
+}
+
+func TestSamplingFilterFirstThenEveryNth(t *testing.T) {
+	inner := &recordingFilter{}
+	sf := NewSamplingFilter(inner, 2, 3, time.Hour)
+	defer sf.Close()
+
+	// 7 identical events: 2 let through (first), then every 3rd of the
+	// remaining 5 (positions 3,4,5,6,7 relative to the window) lets
+	// through #3+3=6 i.e. the 6th overall.
+	for i := 0; i < 7; i++ {
+		sf.Apply(Event{Pri: PriInfo, Message: "flood"})
+	}
+
+	if got := inner.snapshot(); len(got) != 3 {
+		t.Fatalf("expected 3 events through (2 first + 1 every-3rd), got %d: %+v", len(got), got)
+	}
+}
+
+func TestSamplingFilterRolloverEmitsSummary(t *testing.T) {
+	inner := &recordingFilter{}
+	sf := NewSamplingFilter(inner, 1, 2, time.Millisecond)
+	defer sf.Close()
+
+	sf.Apply(Event{Pri: PriWarning, Message: "flood"}) // let through (first)
+	sf.Apply(Event{Pri: PriWarning, Message: "flood"}) // suppressed
+
+	time.Sleep(5 * time.Millisecond)
+
+	sf.Apply(Event{Pri: PriWarning, Message: "flood"}) // new window, let through (first)
+
+	if got := inner.snapshot(); len(got) != 3 {
+		t.Fatalf("expected 2 passthroughs + 1 summary, got %d: %+v", len(got), got)
+	}
+
+	events := inner.snapshot()
+	var summary *Event
+	for i := range events {
+		if events[i].Message != "flood" {
+			summary = &events[i]
+		}
+	}
+	if summary == nil {
+		t.Fatal("expected a summary event among the recorded events")
+	}
+	if summary.Data["_suppressed"] != 1 {
+		t.Fatalf("expected summary to report 1 suppressed, got %+v", summary)
+	}
+}
+
+func TestSamplingFilterFlushesIdleKeyWithoutFurtherTraffic(t *testing.T) {
+	inner := &recordingFilter{}
+	sf := NewSamplingFilter(inner, 1, 2, time.Millisecond)
+	defer sf.Close()
+
+	sf.Apply(Event{Pri: PriWarning, Message: "flood"}) // let through (first)
+	sf.Apply(Event{Pri: PriWarning, Message: "flood"}) // suppressed
+
+	// No further Apply calls for this key: the background sweep, not a
+	// later call, must emit the summary once the window ends.
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if len(inner.snapshot()) >= 2 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	got := inner.snapshot()
+	if len(got) != 2 {
+		t.Fatalf("expected passthrough + idle summary, got %d: %+v", len(got), got)
+	}
+	summary := got[1]
+	if summary.Data["_suppressed"] != 1 {
+		t.Fatalf("expected summary to report 1 suppressed, got %+v", summary)
+	}
+}
+
+func TestNewSamplingFilterNonPositiveIntervalDoesNotPanic(t *testing.T) {
+	inner := &recordingFilter{}
+	sf := NewSamplingFilter(inner, 1, 2, 0)
+	defer sf.Close()
+
+	if ok := sf.Apply(Event{Pri: PriInfo, Message: "x"}); !ok {
+		t.Fatal("Apply returned false")
+	}
+}
+
+func TestSamplingFilterDistinctKeysIndependent(t *testing.T) {
+	inner := &recordingFilter{}
+	sf := NewSamplingFilter(inner, 1, 2, time.Hour)
+	defer sf.Close()
+
+	sf.Apply(Event{Pri: PriInfo, Message: "a"})
+	sf.Apply(Event{Pri: PriInfo, Message: "b"})
+
+	if got := inner.snapshot(); len(got) != 2 {
+		t.Fatalf("distinct keys shouldn't share a sampling budget, got %d events", len(got))
+	}
+}
@@ -0,0 +1,83 @@
+package ln
+
+import (
+	"runtime"
+	"strings"
+)
+
+type frame struct {
+	filename string
+	function string
+	lineno   int
+}
+
+const packageImportPath = "github.com/freeformz/ln."
+
+// internalFrames holds the bare, package-path-stripped names of ln's own
+// logging machinery: callersFrame is invoked from Log, which in turn is
+// invoked either directly or through one of the
+// Emergency/Alert/.../Debug convenience wrappers, as either a
+// package-level function or a *Logger method. callersFrame walks past
+// all of them, however many are chained, to find the actual call site.
+var internalFrames = func() map[string]bool {
+	names := []string{"Log", "(*Logger).Log"}
+	for _, m := range []string{"Emergency", "Alert", "Critical", "Error", "Warning", "Notice", "Info", "Debug"} {
+		names = append(names, m, "(*Logger)."+m)
+	}
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set
+}()
+
+// callersFrame walks the stack outward from its own caller, past ln's
+// logging machinery, to find the frame for the code that actually
+// triggered the log call - regardless of which convenience method
+// (Info, Error, ...) was used to get there.
+func callersFrame() frame {
+	var out frame
+
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs) // skip runtime.Callers and callersFrame itself
+	if n == 0 {
+		return out
+	}
+
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+
+		if bare, inPackage := bareName(f.Function); !inPackage || !internalFrames[bare] {
+			out.filename = f.File
+			out.function = shortName(f.Function)
+			out.lineno = f.Line
+			return out
+		}
+
+		if !more {
+			return out
+		}
+	}
+}
+
+// bareName strips ln's own module path off a fully qualified function
+// name, e.g. "github.com/freeformz/ln.(*Logger).Log" becomes
+// "(*Logger).Log". inPackage reports whether full belongs to this
+// package at all.
+func bareName(full string) (bare string, inPackage bool) {
+	if strings.HasPrefix(full, packageImportPath) {
+		return full[len(packageImportPath):], true
+	}
+	return full, false
+}
+
+// shortName trims a fully qualified function name down to its
+// package-qualified form, e.g. "github.com/freeformz/ln.TestDebug"
+// becomes "ln.TestDebug".
+func shortName(full string) string {
+	if idx := strings.LastIndex(full, "/"); idx >= 0 {
+		return full[idx+1:]
+	}
+	return full
+}
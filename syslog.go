@@ -0,0 +1,318 @@
+package ln
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Facility represents an RFC 5424 syslog facility.
+type Facility int
+
+// The standard RFC 5424 facilities.
+const (
+	FacKernel Facility = iota
+	FacUser
+	FacMail
+	FacDaemon
+	FacAuth
+	FacSyslog
+	FacLPR
+	FacNews
+	FacUUCP
+	FacCron
+	FacAuthPriv
+	FacFTP
+	FacNTP
+	FacAudit
+	FacAlert
+	FacClockDaemon
+	FacLocal0
+	FacLocal1
+	FacLocal2
+	FacLocal3
+	FacLocal4
+	FacLocal5
+	FacLocal6
+	FacLocal7
+)
+
+// localSyslogAddrs are the UNIX domain sockets tried, in order, when
+// NewSyslogFilter is asked to dial the local syslog daemon rather than a
+// remote address. They mirror the sockets the standard library's
+// log/syslog package tries on non-Windows platforms.
+var localSyslogAddrs = []string{
+	"/dev/log",
+	"/var/run/syslog",
+	"/var/run/log",
+}
+
+// severity maps an ln.Priority onto its RFC 5424 severity. The values
+// happen to agree numerically (ln.Priority was modeled on syslog
+// severities to begin with), but the mapping is spelled out explicitly
+// so the two are never accidentally allowed to drift apart.
+func severity(p Priority) int {
+	switch p {
+	case PriEmergency:
+		return 0
+	case PriAlert:
+		return 1
+	case PriCritical:
+		return 2
+	case PriError:
+		return 3
+	case PriWarning:
+		return 4
+	case PriNotice:
+		return 5
+	case PriInfo:
+		return 6
+	case PriDebug:
+		return 7
+	default:
+		return 7
+	}
+}
+
+// dialTimeout bounds how long dialing the syslog daemon, or writing to
+// an already-established connection, may take before it's treated as a
+// stalled link rather than waited on indefinitely.
+const dialTimeout = 5 * time.Second
+
+// SyslogFilter ships Events to a syslog daemon, formatted per RFC 5424.
+// Event.Message becomes MSG and Event.Data is rendered as a single
+// SD-ELEMENT. A connection lost to a network failure is reconnected in
+// the background with exponential backoff rather than propagating the
+// error to the caller; the Event that hit the failing write is itself
+// reported as undelivered (see Apply).
+type SyslogFilter struct {
+	mu       sync.Mutex
+	network  string
+	addr     string
+	tag      string
+	facility Facility
+	hostname string
+	pid      int
+	conn     net.Conn
+
+	closed       bool
+	reconnecting bool
+	reconnectWG  sync.WaitGroup
+	stop         chan struct{}
+}
+
+// NewSyslogFilter dials a syslog daemon and returns a Filter that writes
+// Events to it. If network and addr are both empty, it dials the local
+// syslog daemon over a UNIX domain socket (not supported on Windows, since
+// there is no local syslog daemon to find); otherwise network/addr are
+// passed to net.Dial as-is ("udp"/"tcp"/"unix" with a host:port or socket
+// path). tag is used as the RFC 5424 APP-NAME and facility is combined
+// with each Event's Priority to form the PRI value.
+func NewSyslogFilter(network, addr, tag string, facility Facility) (Filter, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+
+	sf := &SyslogFilter{
+		network:  network,
+		addr:     addr,
+		tag:      tag,
+		facility: facility,
+		hostname: hostname,
+		pid:      os.Getpid(),
+		stop:     make(chan struct{}),
+	}
+
+	conn, err := sf.dial()
+	if err != nil {
+		return nil, err
+	}
+	sf.conn = conn
+
+	return sf, nil
+}
+
+func (s *SyslogFilter) dial() (net.Conn, error) {
+	if s.network != "" || s.addr != "" {
+		return net.DialTimeout(s.network, s.addr, dialTimeout)
+	}
+
+	var lastErr error
+	for _, addr := range localSyslogAddrs {
+		conn, err := net.DialTimeout("unixgram", addr, dialTimeout)
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("ln: no local syslog daemon found: %w", lastErr)
+}
+
+// Apply implements the Filter interface. A write that fails because the
+// connection has gone bad, or that finds no connection currently up, is
+// a hard failure for that Event: a reconnect is kicked off in the
+// background (if one isn't already running) and Apply returns false so
+// the rest of the chain short-circuits for it, the same as any other
+// Filter reporting it couldn't deliver. Once reconnected, later Events
+// go through normally.
+func (s *SyslogFilter) Apply(e Event) bool {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return false
+	}
+	conn := s.conn
+	s.mu.Unlock()
+
+	if conn == nil {
+		s.scheduleReconnect()
+		return false
+	}
+
+	err := conn.SetWriteDeadline(time.Now().Add(dialTimeout))
+	if err == nil {
+		_, err = conn.Write(s.format(e))
+	}
+	if err != nil {
+		s.mu.Lock()
+		if s.conn == conn {
+			s.conn = nil
+		}
+		s.mu.Unlock()
+		conn.Close()
+		s.scheduleReconnect()
+		return false
+	}
+
+	return true
+}
+
+// scheduleReconnect starts a single background goroutine, if one isn't
+// already running, that redials with exponential backoff until it
+// succeeds or the filter is Closed.
+func (s *SyslogFilter) scheduleReconnect() {
+	s.mu.Lock()
+	if s.closed || s.conn != nil || s.reconnecting {
+		s.mu.Unlock()
+		return
+	}
+	s.reconnecting = true
+	s.reconnectWG.Add(1)
+	s.mu.Unlock()
+
+	go func() {
+		defer s.reconnectWG.Done()
+
+		backoff := 100 * time.Millisecond
+		const maxBackoff = 30 * time.Second
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-time.After(backoff):
+			}
+
+			conn, err := s.dial()
+			if err == nil {
+				s.mu.Lock()
+				s.reconnecting = false
+				if s.closed {
+					s.mu.Unlock()
+					conn.Close()
+					return
+				}
+				s.conn = conn
+				s.mu.Unlock()
+				return
+			}
+
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+		}
+	}()
+}
+
+// format renders e as an RFC 5424 formatted syslog message, with
+// Event.Data carried as a single SD-ELEMENT ([ln@32473 key="value" ...]).
+func (s *SyslogFilter) format(e Event) []byte {
+	pri := int(s.facility)*8 + severity(e.Pri)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s %d - ", pri, e.Time.UTC().Format(time.RFC3339Nano), s.hostname, s.tag, s.pid)
+	buf.Write(s.structuredData(e.Data))
+	buf.WriteByte(' ')
+	buf.WriteString(e.Message)
+	buf.WriteByte('\n')
+
+	return buf.Bytes()
+}
+
+func (s *SyslogFilter) structuredData(data F) []byte {
+	if len(data) == 0 {
+		return []byte("-")
+	}
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("[ln@32473")
+	for _, k := range keys {
+		buf.WriteByte(' ')
+		buf.WriteString(k)
+		buf.WriteString(`="`)
+		buf.WriteString(escapeSDValue(fmt.Sprint(data[k])))
+		buf.WriteByte('"')
+	}
+	buf.WriteByte(']')
+
+	return buf.Bytes()
+}
+
+// escapeSDValue escapes the characters RFC 5424 requires inside a
+// PARAM-VALUE: backslash, double-quote and closing bracket.
+func escapeSDValue(s string) string {
+	var buf bytes.Buffer
+	for _, r := range s {
+		switch r {
+		case '\\', '"', ']':
+			buf.WriteByte('\\')
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// Run implements the Filter interface.
+func (s *SyslogFilter) Run() {}
+
+// Close implements the Filter interface, stopping any in-flight reconnect
+// attempt and closing the underlying connection.
+func (s *SyslogFilter) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	conn := s.conn
+	s.conn = nil
+	s.mu.Unlock()
+
+	close(s.stop)
+	s.reconnectWG.Wait()
+
+	if conn != nil {
+		conn.Close()
+	}
+}
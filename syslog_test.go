@@ -0,0 +1,96 @@
+package ln
+
+import (
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyslogFilter(t *testing.T) {
+	sock, err := net.ListenPacket("unixgram", "")
+	if err != nil {
+		t.Fatalf("ListenPacket: %v", err)
+	}
+	defer sock.Close()
+
+	f, err := NewSyslogFilter("unixgram", sock.LocalAddr().String(), "ln-test", FacLocal0)
+	if err != nil {
+		t.Fatalf("NewSyslogFilter: %v", err)
+	}
+	defer f.Close()
+
+	sf := f.(*SyslogFilter)
+	sf.hostname = "test-host"
+
+	ok := f.Apply(Event{
+		Pri:     PriError,
+		Time:    time.Now(),
+		Message: "boom",
+		Data:    F{"key": "value"},
+	})
+	if !ok {
+		t.Fatal("Apply returned false for a healthy connection")
+	}
+
+	buf := make([]byte, 1024)
+	sock.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := sock.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom: %v", err)
+	}
+	got := string(buf[:n])
+
+	// facility FacLocal0 (16) * 8 + severity(PriError) (3) == 131
+	for _, want := range []string{
+		"<131>1 ",
+		"test-host ln-test ",
+		`[ln@32473 key="value"]`,
+		"boom",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("%q not in %q", want, got)
+		}
+	}
+}
+
+func TestSyslogFilterHardFailureReturnsFalse(t *testing.T) {
+	sock, err := net.ListenPacket("unixgram", "")
+	if err != nil {
+		t.Fatalf("ListenPacket: %v", err)
+	}
+	defer sock.Close()
+
+	f, err := NewSyslogFilter("unixgram", sock.LocalAddr().String(), "ln-test", FacLocal0)
+	if err != nil {
+		t.Fatalf("NewSyslogFilter: %v", err)
+	}
+	defer f.Close()
+
+	sf := f.(*SyslogFilter)
+	sf.mu.Lock()
+	sf.conn.Close() // simulate a dead connection without tearing down the dial target
+	sf.mu.Unlock()
+
+	// A burst of concurrent Apply calls while disconnected must each
+	// report the failure (false) and must not spawn a redial per call.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok := f.Apply(Event{Pri: PriError, Time: time.Now(), Message: "boom"}); ok {
+				t.Error("Apply returned true writing to a closed connection")
+			}
+		}()
+	}
+	wg.Wait()
+
+	sf.mu.Lock()
+	reconnecting := sf.reconnecting
+	sf.mu.Unlock()
+	if !reconnecting {
+		t.Fatal("expected a reconnect attempt to be in flight")
+	}
+}